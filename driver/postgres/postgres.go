@@ -2,11 +2,14 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/lib/pq"
 	"github.com/mattes/migrate/driver"
@@ -15,13 +18,101 @@ import (
 )
 
 type Driver struct {
-	db *sql.DB
+	db       *sql.DB
+	config   *config
+	logger   Logger
+	lockConn *sql.Conn
 }
 
-const tableName = "schema_migrations"
+// Logger receives the driver's verbose SQL tracing, when enabled via
+// SetLogger and x-verbose=true. It's satisfied by the standard library's
+// *log.Logger.
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Printf(format string, args ...interface{}) {}
+
+// SetLogger installs logger to receive this driver's verbose SQL tracing.
+// It has no effect unless the connection URL also sets x-verbose=true; this
+// lets a caller wire up a logger once and toggle tracing per-environment via
+// the URL instead of code.
+func (driver *Driver) SetLogger(logger Logger) {
+	driver.logger = logger
+}
+
+// logf writes a verbose trace line if x-verbose=true was set on the
+// connection URL; otherwise it's a no-op, preserving the driver's default
+// silence.
+func (driver *Driver) logf(format string, args ...interface{}) {
+	if !driver.config.verbose {
+		return
+	}
+	driver.logger.Printf(format, args...)
+}
+
+// config holds the options the driver accepts via "x-"-prefixed query
+// parameters on the connection URL. They are stripped from the URL before
+// it's handed to sql.Open so lib/pq never sees them.
+type config struct {
+	advisoryLock     advisoryLockMode
+	statementTimeout time.Duration
+	verbose          bool
+	migrationsTable  string
+	baseline         uint64
+}
+
+type advisoryLockMode int
+
+const (
+	// advisoryLockWait blocks until the lock is available.
+	advisoryLockWait advisoryLockMode = iota
+	// advisoryLockTry fails immediately if the lock is held elsewhere.
+	advisoryLockTry
+)
+
+const defaultTableName = "schema_migrations"
+
+// tableName returns the name of the migrations table, which is
+// "schema_migrations" unless overridden via x-migrations-table, e.g. to
+// point this driver at a table already created by a different tool. Since
+// x-migrations-table comes straight from the URL, callers that splice it
+// into SQL text must use quotedTableName instead; tableName itself is only
+// safe to use as a bound query parameter (e.g. hashtext($1)).
+func (driver *Driver) tableName() string {
+	return driver.config.migrationsTable
+}
+
+// quotedTableName returns tableName quoted as a Postgres identifier, for
+// callers that have to build it into SQL text (Postgres has no placeholder
+// syntax for identifiers). It must be used everywhere tableName is
+// concatenated into a query, since x-migrations-table is attacker/operator
+// controlled and unescaped concatenation would allow identifier injection.
+func (driver *Driver) quotedTableName() string {
+	return pq.QuoteIdentifier(driver.config.migrationsTable)
+}
 
-func (driver *Driver) Initialize(url string) error {
-	db, err := sql.Open("postgres", url)
+var driverParamNames = []string{"x-advisory-lock", "x-statement-timeout", "x-verbose", "x-migrations-table", "x-baseline"}
+
+func (driver *Driver) Initialize(rawurl string) error {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := parseConfig(u.Query())
+	if err != nil {
+		return err
+	}
+	driver.config = cfg
+
+	if driver.logger == nil {
+		driver.logger = noopLogger{}
+	}
+
+	db, err := sql.Open("postgres", filterDriverParams(u).String())
 	if err != nil {
 		return err
 	}
@@ -30,31 +121,198 @@ func (driver *Driver) Initialize(url string) error {
 	}
 	driver.db = db
 
-	if err := driver.ensureVersionTableExists(); err != nil {
+	if err := driver.Lock(context.Background()); err != nil {
+		return err
+	}
+
+	// Past this point the advisory lock is held on driver.lockConn, so any
+	// failure must release it (and close driver.db) before returning —
+	// otherwise the lock outlives this Driver and blocks every future Lock
+	// against this table until the backend holding it is killed by hand.
+	created, err := driver.ensureVersionTableExists()
+	if err != nil {
+		driver.Unlock(context.Background())
+		driver.db.Close()
 		return err
 	}
+	if created && driver.config.baseline > 0 {
+		if err := driver.Baseline(driver.config.baseline); err != nil {
+			driver.Unlock(context.Background())
+			driver.db.Close()
+			return err
+		}
+	}
 	return nil
 }
 
+// parseConfig reads the "x-"-prefixed query parameters recognized by this
+// driver out of a connection URL's query values.
+func parseConfig(q url.Values) (*config, error) {
+	cfg := &config{migrationsTable: defaultTableName}
+
+	switch mode := q.Get("x-advisory-lock"); mode {
+	case "", "wait":
+		cfg.advisoryLock = advisoryLockWait
+	case "try":
+		cfg.advisoryLock = advisoryLockTry
+	default:
+		return nil, fmt.Errorf("x-advisory-lock: invalid mode %q", mode)
+	}
+
+	if raw := q.Get("x-statement-timeout"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("x-statement-timeout: %v", err)
+		}
+		cfg.statementTimeout = d
+	}
+
+	if raw := q.Get("x-verbose"); raw != "" {
+		verbose, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("x-verbose: %v", err)
+		}
+		cfg.verbose = verbose
+	}
+
+	if raw := q.Get("x-migrations-table"); raw != "" {
+		cfg.migrationsTable = raw
+	}
+
+	if raw := q.Get("x-baseline"); raw != "" {
+		v, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("x-baseline: %v", err)
+		}
+		cfg.baseline = v
+	}
+
+	return cfg, nil
+}
+
+// filterDriverParams returns a copy of u with the driver's own "x-"-prefixed
+// query parameters removed, so they aren't forwarded to lib/pq.
+func filterDriverParams(u *url.URL) *url.URL {
+	filtered := *u
+	q := filtered.Query()
+	for _, name := range driverParamNames {
+		q.Del(name)
+	}
+	filtered.RawQuery = q.Encode()
+	return &filtered
+}
+
 func (driver *Driver) Close() error {
-	if err := driver.db.Close(); err != nil {
-		return err
+	// db.Close() must run even if Unlock fails, or a failed unlock also
+	// leaks the whole connection pool on top of the held advisory lock.
+	unlockErr := driver.Unlock(context.Background())
+	closeErr := driver.db.Close()
+	if unlockErr != nil {
+		if closeErr != nil {
+			return fmt.Errorf("unlock: %v (and close: %v)", unlockErr, closeErr)
+		}
+		return unlockErr
 	}
-	return nil
+	return closeErr
 }
 
-func (driver *Driver) ensureVersionTableExists() error {
-	if _, err := driver.db.Exec("CREATE TABLE IF NOT EXISTS " + tableName + " (version int not null primary key);"); err != nil {
+// Lock acquires a Postgres advisory lock scoped to the migrations table, so
+// that only one process at a time can run a batch of migrations against it.
+// It's held for the lifetime of the Driver, from Initialize through Close,
+// which brackets exactly one migration batch. In x-advisory-lock=try mode,
+// Lock returns immediately with an error instead of blocking if the lock is
+// already held by another session.
+//
+// pg_advisory_lock/pg_advisory_unlock are scoped to the session that took
+// the lock, so this pins a single *sql.Conn checked out of driver.db for the
+// lock's entire lifetime rather than letting the pool hand out whichever
+// connection is free — acquiring and releasing on different connections
+// would silently leave the lock held forever.
+func (driver *Driver) Lock(ctx context.Context) error {
+	conn, err := driver.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	if driver.config.advisoryLock == advisoryLockTry {
+		var acquired bool
+		if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(hashtext($1)::bigint)", driver.tableName()).Scan(&acquired); err != nil {
+			conn.Close()
+			return err
+		}
+		if !acquired {
+			conn.Close()
+			return errors.New("postgres: could not acquire advisory lock, already held by another session")
+		}
+	} else if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock(hashtext($1)::bigint)", driver.tableName()); err != nil {
+		conn.Close()
 		return err
 	}
+
+	driver.lockConn = conn
 	return nil
 }
 
+// Unlock releases the advisory lock acquired by Lock and returns its pinned
+// connection to the pool. It's a no-op if Lock was never called.
+func (driver *Driver) Unlock(ctx context.Context) error {
+	if driver.lockConn == nil {
+		return nil
+	}
+	conn := driver.lockConn
+	driver.lockConn = nil
+	defer conn.Close()
+
+	_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock(hashtext($1)::bigint)", driver.tableName())
+	return err
+}
+
+// ensureVersionTableExists creates the migrations table (and brings an
+// existing one up to date) and reports whether the table didn't exist yet,
+// so Initialize knows whether it's safe to apply an x-baseline.
+func (driver *Driver) ensureVersionTableExists() (created bool, err error) {
+	var existedBefore bool
+	if err := driver.db.QueryRow("SELECT to_regclass($1) IS NOT NULL", driver.quotedTableName()).Scan(&existedBefore); err != nil {
+		return false, err
+	}
+
+	if _, err := driver.db.Exec("CREATE TABLE IF NOT EXISTS " + driver.quotedTableName() + " (version bigint not null primary key);"); err != nil {
+		return false, err
+	}
+	// These are added out-of-line, rather than folded into the CREATE TABLE
+	// above, so that databases which already have a schema_migrations table
+	// from an older version of this driver pick them up too.
+	if _, err := driver.db.Exec("ALTER TABLE " + driver.quotedTableName() + " ADD COLUMN IF NOT EXISTS dirty boolean not null default false"); err != nil {
+		return false, err
+	}
+	if _, err := driver.db.Exec("ALTER TABLE " + driver.quotedTableName() + " ADD COLUMN IF NOT EXISTS applied_at timestamptz default now()"); err != nil {
+		return false, err
+	}
+	if _, err := driver.db.Exec("ALTER TABLE " + driver.quotedTableName() + " ALTER COLUMN applied_at SET DEFAULT now()"); err != nil {
+		return false, err
+	}
+	if _, err := driver.db.Exec("ALTER TABLE " + driver.quotedTableName() + " ADD COLUMN IF NOT EXISTS duration_ms bigint"); err != nil {
+		return false, err
+	}
+	return !existedBefore, nil
+}
+
 func (driver *Driver) FilenameExtension() string {
 	return "sql"
 }
 
+// Migrate runs f without a caller-provided context. It's kept for backwards
+// compatibility with the driver.Driver interface; new callers that need
+// cancellation or deadlines should use MigrateContext.
 func (driver *Driver) Migrate(f file.File, pipe chan interface{}) {
+	driver.MigrateContext(context.Background(), f, pipe)
+}
+
+// MigrateContext runs f like Migrate, but threads ctx through every query so
+// the caller can cancel a stuck migration instead of hanging the whole batch.
+// If x-statement-timeout was set on the connection URL, it's also enforced
+// server-side for the duration of this migration.
+func (driver *Driver) MigrateContext(ctx context.Context, f file.File, pipe chan interface{}) {
 	defer close(pipe)
 	pipe <- f
 
@@ -64,27 +322,61 @@ func (driver *Driver) Migrate(f file.File, pipe chan interface{}) {
 	}
 
 	if !canUseTransaction(string(f.Content)) {
-		if err := internalMigrate(driver.db, f); err != nil {
+		if err := driver.markDirty(ctx, f); err != nil {
+			pipe <- err
+			return
+		}
+
+		// SET statement_timeout and the eventual RESET have to run on the
+		// same session as the migration itself, so pin a single connection
+		// instead of letting driver.db hand out a different one to each.
+		conn, err := driver.db.Conn(ctx)
+		if err != nil {
+			pipe <- err
+			return
+		}
+		defer conn.Close()
+
+		if err := driver.setSessionStatementTimeout(ctx, conn); err != nil {
+			pipe <- err
+			return
+		}
+		defer driver.resetSessionStatementTimeout(ctx, conn)
+
+		if err := driver.internalMigrate(ctx, conn, f); err != nil {
 			pipe <- err
 		}
 		return
 	}
 
-	tx, err := driver.db.Begin()
+	driver.logf("postgres: version %d: BEGIN", f.Version)
+	tx, err := driver.db.BeginTx(ctx, nil)
 	if err != nil {
 		pipe <- err
 		return
 	}
 
-	if err := internalMigrate(tx, f); err != nil {
+	if err := driver.setLocalStatementTimeout(ctx, tx); err != nil {
+		pipe <- err
+
+		driver.logf("postgres: version %d: ROLLBACK", f.Version)
+		if err := tx.Rollback(); err != nil {
+			pipe <- err
+		}
+		return
+	}
+
+	if err := driver.internalMigrate(ctx, tx, f); err != nil {
 		pipe <- err
 
+		driver.logf("postgres: version %d: ROLLBACK", f.Version)
 		if err := tx.Rollback(); err != nil {
 			pipe <- err
 		}
 		return
 	}
 
+	driver.logf("postgres: version %d: COMMIT", f.Version)
 	if err := tx.Commit(); err != nil {
 		pipe <- err
 		return
@@ -96,32 +388,118 @@ func canUseTransaction(sql string) bool {
 	return !strings.HasPrefix(sql, "-- migrate: no-transaction\n")
 }
 
+func shouldSplitStatements(sql string) bool {
+	return strings.HasPrefix(sql, "-- migrate: split-statements\n")
+}
+
+// markDirty records that f.Version is about to be applied outside of a
+// transaction, before running it. If the migration fails partway through,
+// the dirty flag stays set and Version refuses to report a clean state
+// until an operator calls ForceVersion.
+func (driver *Driver) markDirty(ctx context.Context, f file.File) error {
+	if f.Direction == direction.Up {
+		if _, err := driver.db.ExecContext(ctx, "INSERT INTO "+driver.quotedTableName()+" (version, dirty) VALUES ($1, true) ON CONFLICT (version) DO UPDATE SET dirty = true", f.Version); err != nil {
+			return err
+		}
+	} else if f.Direction == direction.Down {
+		if _, err := driver.db.ExecContext(ctx, "UPDATE "+driver.quotedTableName()+" SET dirty = true WHERE version = $1", f.Version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setLocalStatementTimeout applies x-statement-timeout to tx only, via
+// SET LOCAL, so it's automatically undone when the transaction ends.
+func (driver *Driver) setLocalStatementTimeout(ctx context.Context, tx *sql.Tx) error {
+	if driver.config.statementTimeout == 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", driver.config.statementTimeout.Milliseconds()))
+	return err
+}
+
+// setSessionStatementTimeout applies x-statement-timeout for non-transactional
+// migrations, which have no transaction to scope SET LOCAL to. conn must be
+// the same pinned connection the migration itself runs on, and must be paired
+// with resetSessionStatementTimeout on that same conn, since a plain SET
+// persists for the life of the session rather than the statement.
+func (driver *Driver) setSessionStatementTimeout(ctx context.Context, conn *sql.Conn) error {
+	if driver.config.statementTimeout == 0 {
+		return nil
+	}
+	_, err := conn.ExecContext(ctx, fmt.Sprintf("SET statement_timeout = %d", driver.config.statementTimeout.Milliseconds()))
+	return err
+}
+
+func (driver *Driver) resetSessionStatementTimeout(ctx context.Context, conn *sql.Conn) error {
+	if driver.config.statementTimeout == 0 {
+		return nil
+	}
+	_, err := conn.ExecContext(ctx, "SET statement_timeout = 0")
+	return err
+}
+
 type dbExecer interface {
-	Exec(query string, args ...interface{}) (sql.Result, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 }
 
-func internalMigrate(tx dbExecer, f file.File) error {
-	if _, err := tx.Exec(string(f.Content)); err != nil {
-		pqErr := err.(*pq.Error)
-		offset, err := strconv.Atoi(pqErr.Position)
-		if err == nil && offset >= 0 {
-			lineNo, columnNo := file.LineColumnFromOffset(f.Content, offset-1)
-			errorPart := file.LinesBeforeAndAfter(f.Content, lineNo, 5, 5, true)
-			return errors.New(fmt.Sprintf("%s %v: %s in line %v, column %v:\n\n%s", pqErr.Severity, pqErr.Code, pqErr.Message, lineNo, columnNo, string(errorPart)))
-		} else {
-			return errors.New(fmt.Sprintf("%s %v: %s", pqErr.Severity, pqErr.Code, pqErr.Message))
+// pqExecError turns a failed Exec's *pq.Error into the line/column-annotated
+// message this driver has always reported, locating the offset within
+// content (the exact text that was sent to Postgres, which in split-statement
+// mode is a single statement rather than the whole file). prefix is prepended
+// as-is, e.g. to identify which statement failed. Errors that aren't
+// *pq.Error (e.g. context.Canceled/DeadlineExceeded from a cancelled ctx) are
+// reported as a new error holding just their message with prefix prepended,
+// since there's no SQL position to annotate; the original error value is not
+// wrapped.
+func pqExecError(err error, content []byte, prefix string) error {
+	pqErr, ok := err.(*pq.Error)
+	if !ok {
+		return errors.New(fmt.Sprintf("%s%v", prefix, err))
+	}
+	offset, convErr := strconv.Atoi(pqErr.Position)
+	if convErr == nil && offset >= 0 {
+		lineNo, columnNo := file.LineColumnFromOffset(content, offset-1)
+		errorPart := file.LinesBeforeAndAfter(content, lineNo, 5, 5, true)
+		return errors.New(fmt.Sprintf("%s%s %v: %s in line %v, column %v:\n\n%s", prefix, pqErr.Severity, pqErr.Code, pqErr.Message, lineNo, columnNo, string(errorPart)))
+	}
+	return errors.New(fmt.Sprintf("%s%s %v: %s", prefix, pqErr.Severity, pqErr.Code, pqErr.Message))
+}
+
+func (driver *Driver) internalMigrate(ctx context.Context, tx dbExecer, f file.File) error {
+	start := time.Now()
+
+	content := string(f.Content)
+	if shouldSplitStatements(content) {
+		for i, stmt := range splitStatements(content) {
+			driver.logf("postgres: version %d: statement %d: %s", f.Version, i+1, stmt)
+			stmtStart := time.Now()
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return pqExecError(err, []byte(stmt), fmt.Sprintf("statement %d: ", i+1))
+			}
+			driver.logf("postgres: version %d: statement %d: done in %s", f.Version, i+1, time.Since(stmtStart))
+		}
+	} else {
+		driver.logf("postgres: version %d: %s", f.Version, content)
+		if _, err := tx.ExecContext(ctx, content); err != nil {
+			return pqExecError(err, f.Content, "")
 		}
 	}
+	durationMs := int64(time.Since(start) / time.Millisecond)
+	driver.logf("postgres: version %d: done in %dms", f.Version, durationMs)
 
 	// Update direction after the migration succeeds. If there's an error and
-	// we're not in a transaction, manual cleanup will be necessary; it's
-	// better to stop running new migrations until the current one succeeds.
+	// we're not in a transaction, the dirty flag set by markDirty is left in
+	// place; manual cleanup will be necessary and it's better to stop running
+	// new migrations until the current one succeeds.
 	if f.Direction == direction.Up {
-		if _, err := tx.Exec("INSERT INTO "+tableName+" (version) VALUES ($1)", f.Version); err != nil {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO "+driver.quotedTableName()+" (version, dirty, applied_at, duration_ms) VALUES ($1, false, now(), $2) "+
+			"ON CONFLICT (version) DO UPDATE SET dirty = false, applied_at = now(), duration_ms = $2", f.Version, durationMs); err != nil {
 			return err
 		}
 	} else if f.Direction == direction.Down {
-		if _, err := tx.Exec("DELETE FROM "+tableName+" WHERE version=$1", f.Version); err != nil {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+driver.quotedTableName()+" WHERE version=$1", f.Version); err != nil {
 			return err
 		}
 	}
@@ -131,17 +509,98 @@ func internalMigrate(tx dbExecer, f file.File) error {
 
 func (driver *Driver) Version() (uint64, error) {
 	var version uint64
-	err := driver.db.QueryRow("SELECT version FROM " + tableName + " ORDER BY version DESC LIMIT 1").Scan(&version)
+	var dirty bool
+	err := driver.db.QueryRow("SELECT version, dirty FROM "+driver.quotedTableName()+" ORDER BY version DESC LIMIT 1").Scan(&version, &dirty)
 	switch {
 	case err == sql.ErrNoRows:
 		return 0, nil
 	case err != nil:
 		return 0, err
+	case dirty:
+		return version, ErrDirty{version}
 	default:
 		return version, nil
 	}
 }
 
+// ErrDirty is returned by Version when the latest recorded migration is
+// marked dirty, meaning a previous no-transaction migration failed partway
+// through and left the schema in an unknown state. Operators need to inspect
+// the database by hand and call ForceVersion once they've confirmed (and, if
+// necessary, repaired) its state.
+type ErrDirty struct {
+	Version uint64
+}
+
+func (e ErrDirty) Error() string {
+	return fmt.Sprintf("Dirty database version %d. Fix and force version.", e.Version)
+}
+
+// ForceVersion clears the dirty flag on v's row, inserting it if it's not
+// already present, without running any migration. It's the operator's escape
+// hatch for recovering from ErrDirty once the schema has been manually
+// verified. It only ever touches v's own row: the rest of the table's
+// history (used by Status and by down-migrations below v) is left intact.
+func (driver *Driver) ForceVersion(v uint64) error {
+	_, err := driver.db.Exec("INSERT INTO "+driver.quotedTableName()+" (version, dirty, applied_at) VALUES ($1, false, now()) "+
+		"ON CONFLICT (version) DO UPDATE SET dirty = false", v)
+	return err
+}
+
+// Baseline records versions 1..version as already applied, without running
+// any of their migrations. It's for adopting this driver against a database
+// whose schema was created by another tool (or by hand): point it at the
+// existing database and baseline it to the last version that tool applied,
+// then layer new migrations on top. Versions already present are left alone.
+func (driver *Driver) Baseline(version uint64) error {
+	tx, err := driver.db.Begin()
+	if err != nil {
+		return err
+	}
+	for v := uint64(1); v <= version; v++ {
+		if _, err := tx.Exec("INSERT INTO "+driver.quotedTableName()+" (version, dirty, applied_at) VALUES ($1, false, now()) ON CONFLICT (version) DO NOTHING", v); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// MigrationStatus describes one row recorded in schema_migrations.
+type MigrationStatus struct {
+	Version    uint64
+	Direction  direction.Direction
+	AppliedAt  time.Time
+	DurationMs int64
+}
+
+// Status returns the full migration history currently recorded in
+// schema_migrations, ordered by version, so callers can build dashboards or
+// detect out-of-order applications (e.g. 1, 4, 2, 3). Every row returned was
+// applied in the up direction: once a migration is reverted, its row is
+// removed and it no longer appears here.
+func (driver *Driver) Status(ctx context.Context) ([]MigrationStatus, error) {
+	rows, err := driver.db.QueryContext(ctx, "SELECT version, applied_at, duration_ms FROM "+driver.quotedTableName()+" ORDER BY version ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var statuses []MigrationStatus
+	for rows.Next() {
+		s := MigrationStatus{Direction: direction.Up}
+		var appliedAt sql.NullTime
+		var durationMs sql.NullInt64
+		if err := rows.Scan(&s.Version, &appliedAt, &durationMs); err != nil {
+			return nil, err
+		}
+		s.AppliedAt = appliedAt.Time
+		s.DurationMs = durationMs.Int64
+		statuses = append(statuses, s)
+	}
+	return statuses, rows.Err()
+}
+
 func init() {
 	driver.RegisterDriver("postgres", &Driver{})
 }
@@ -0,0 +1,171 @@
+package postgres
+
+import "strings"
+
+// splitStatements splits a migration's SQL into the individual statements
+// delimited by top-level semicolons, for "-- migrate: split-statements" mode.
+// Each statement keeps its terminating semicolon, if it had one.
+// It tracks just enough of Postgres' lexical rules to find those semicolons
+// correctly: single-quoted strings ('' escapes, plus E'...' strings where
+// backslash also escapes), dollar-quoted strings ($tag$...$tag$), -- line
+// comments, and /* ... */ block comments, which Postgres allows to nest.
+// Empty statements (e.g. a trailing semicolon, or comment-only segments) are
+// dropped.
+func splitStatements(sql string) []string {
+	var statements []string
+	var cur strings.Builder
+	// hasCode is set once the current segment holds anything other than
+	// whitespace and comments.
+	hasCode := false
+
+	// flush ends the current statement, appending term (its terminating
+	// semicolon, if any) to it.
+	flush := func(term string) {
+		if hasCode {
+			statements = append(statements, strings.TrimSpace(cur.String())+term)
+		}
+		cur.Reset()
+		hasCode = false
+	}
+
+	n := len(sql)
+	blockCommentDepth := 0
+	for i := 0; i < n; {
+		if blockCommentDepth > 0 {
+			switch {
+			case strings.HasPrefix(sql[i:], "/*"):
+				blockCommentDepth++
+				cur.WriteString(sql[i : i+2])
+				i += 2
+			case strings.HasPrefix(sql[i:], "*/"):
+				blockCommentDepth--
+				cur.WriteString(sql[i : i+2])
+				i += 2
+			default:
+				cur.WriteByte(sql[i])
+				i++
+			}
+			continue
+		}
+
+		switch c := sql[i]; {
+		case strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end == -1 {
+				cur.WriteString(sql[i:])
+				i = n
+			} else {
+				cur.WriteString(sql[i : i+end+1])
+				i += end + 1
+			}
+
+		case strings.HasPrefix(sql[i:], "/*"):
+			blockCommentDepth = 1
+			cur.WriteString(sql[i : i+2])
+			i += 2
+
+		case c == '\'':
+			end := scanQuotedString(sql, i)
+			cur.WriteString(sql[i:end])
+			i = end
+			hasCode = true
+
+		case (c == 'e' || c == 'E') && i+1 < n && sql[i+1] == '\'':
+			end := scanEscapeString(sql, i+1)
+			cur.WriteString(sql[i:end])
+			i = end
+			hasCode = true
+
+		case c == '$':
+			hasCode = true
+			if delimEnd, ok := dollarQuoteDelim(sql, i); ok {
+				end := scanDollarQuoted(sql, delimEnd, sql[i:delimEnd])
+				cur.WriteString(sql[i:end])
+				i = end
+			} else {
+				cur.WriteByte(c)
+				i++
+			}
+
+		case c == ';':
+			flush(";")
+			i++
+
+		default:
+			if c != ' ' && c != '\t' && c != '\n' && c != '\r' && c != '\f' && c != '\v' {
+				hasCode = true
+			}
+			cur.WriteByte(c)
+			i++
+		}
+	}
+	flush("")
+
+	return statements
+}
+
+// scanQuotedString returns the index just past the end of the single-quoted
+// string starting at sql[start] (sql[start] == '\''), treating a doubled
+// quote ('') as an escaped quote rather than the end of the string.
+func scanQuotedString(sql string, start int) int {
+	n := len(sql)
+	for i := start + 1; i < n; i++ {
+		if sql[i] != '\'' {
+			continue
+		}
+		if i+1 < n && sql[i+1] == '\'' {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return n
+}
+
+// scanEscapeString is like scanQuotedString but for E'...' strings, where a
+// backslash also escapes the following character (so \' doesn't end the
+// string and \\ doesn't consume the quote after it).
+func scanEscapeString(sql string, quoteStart int) int {
+	n := len(sql)
+	for i := quoteStart + 1; i < n; i++ {
+		switch sql[i] {
+		case '\\':
+			i++
+		case '\'':
+			if i+1 < n && sql[i+1] == '\'' {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return n
+}
+
+// dollarQuoteDelim reports whether sql[start:] begins a dollar-quote
+// delimiter ($tag$, where tag is an identifier or empty) and, if so, the
+// index just past its closing '$'.
+func dollarQuoteDelim(sql string, start int) (int, bool) {
+	n := len(sql)
+	for i := start + 1; i < n; i++ {
+		switch c := sql[i]; {
+		case c == '$':
+			return i + 1, true
+		case c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9'):
+			continue
+		default:
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// scanDollarQuoted returns the index just past the closing delim, searching
+// from bodyStart onward. If delim never recurs, the string runs to the end
+// of the input, same as an unterminated quoted string would.
+func scanDollarQuoted(sql string, bodyStart int, delim string) int {
+	if idx := strings.Index(sql[bodyStart:], delim); idx != -1 {
+		return bodyStart + idx + len(delim)
+	}
+	return len(sql)
+}
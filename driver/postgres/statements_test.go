@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{
+			name: "basic split",
+			sql:  "SELECT 1; SELECT 2;",
+			want: []string{"SELECT 1;", "SELECT 2;"},
+		},
+		{
+			name: "no trailing semicolon",
+			sql:  "SELECT 1; SELECT 2",
+			want: []string{"SELECT 1;", "SELECT 2"},
+		},
+		{
+			name: "empty statements dropped",
+			sql:  "SELECT 1;;;  ;SELECT 2;",
+			want: []string{"SELECT 1;", "SELECT 2;"},
+		},
+		{
+			name: "only whitespace and semicolons",
+			sql:  "  ;  ;\n;\t;  ",
+			want: nil,
+		},
+		{
+			name: "single-quoted string with doubled-quote escape",
+			sql:  "INSERT INTO t (v) VALUES ('a;b''c;d');",
+			want: []string{"INSERT INTO t (v) VALUES ('a;b''c;d');"},
+		},
+		{
+			name: "E-string with backslash escapes",
+			sql:  `INSERT INTO t (v) VALUES (E'a;\'b;c');`,
+			want: []string{`INSERT INTO t (v) VALUES (E'a;\'b;c');`},
+		},
+		{
+			name: "line comment containing a semicolon",
+			sql:  "SELECT 1; -- comment; with semicolons\nSELECT 2;",
+			want: []string{"SELECT 1;", "-- comment; with semicolons\nSELECT 2;"},
+		},
+		{
+			name: "nested block comments",
+			sql:  "SELECT 1 /* outer /* inner; */ still commented; */;",
+			want: []string{"SELECT 1 /* outer /* inner; */ still commented; */;"},
+		},
+		{
+			name: "plain dollar-quoted string",
+			sql:  "SELECT $$a;b$$;",
+			want: []string{"SELECT $$a;b$$;"},
+		},
+		{
+			name: "tagged dollar-quoted string with nested dollar signs",
+			sql:  "CREATE FUNCTION f() RETURNS void AS $body$ BEGIN; x := $1; END; $body$ LANGUAGE plpgsql;",
+			want: []string{"CREATE FUNCTION f() RETURNS void AS $body$ BEGIN; x := $1; END; $body$ LANGUAGE plpgsql;"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitStatements(tt.sql)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitStatements(%q) = %#v, want %#v", tt.sql, got, tt.want)
+			}
+		})
+	}
+}